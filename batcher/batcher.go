@@ -0,0 +1,113 @@
+package batcher
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when adding an item to a closed Batcher.
+var ErrClosed = errors.New("batcher is closed")
+
+// Clock creates the timers of a Batcher. It can be replaced by a fake clock to trigger time-based flushes in tests.
+type Clock interface {
+	// AfterFunc calls f after the duration elapses and returns a function that stops the timer.
+	// Like time.AfterFunc, f must not be called before AfterFunc returns.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+// Batcher collects items and flushes them as a batch once maxSize items are added or maxDelay has elapsed since the first
+// item of the batch, whichever comes first. Flushes are serialized and happen in the order the items were added.
+// Since the batch is flushed while holding the lock, Add blocks until a flush in progress returns, and flush must not call Add.
+type Batcher[T any] struct {
+	mu         sync.Mutex
+	items      []T
+	maxSize    int
+	maxDelay   time.Duration
+	flush      func([]T)
+	clock      Clock
+	stopTimer  func() bool
+	generation int // incremented on every flush, so that the timer of a batch already flushed is ignored
+	closed     bool
+}
+
+// New creates a Batcher. A maxSize or maxDelay that isn't positive disables the corresponding trigger.
+func New[T any](maxSize int, maxDelay time.Duration, flush func([]T)) *Batcher[T] {
+	return NewWithClock(maxSize, maxDelay, realClock{}, flush)
+}
+
+// NewWithClock is like New, but creates the timers with the given clock.
+func NewWithClock[T any](maxSize int, maxDelay time.Duration, clock Clock, flush func([]T)) *Batcher[T] {
+	return &Batcher[T]{
+		maxSize:  maxSize,
+		maxDelay: maxDelay,
+		flush:    flush,
+		clock:    clock,
+	}
+}
+
+// Add adds an item to the current batch, flushing it if it reaches the maximum size.
+func (b *Batcher[T]) Add(item T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	b.items = append(b.items, item)
+	if len(b.items) == 1 && b.maxDelay > 0 {
+		generation := b.generation
+		b.stopTimer = b.clock.AfterFunc(b.maxDelay, func() {
+			b.flushGeneration(generation)
+		})
+	}
+	if b.maxSize > 0 && len(b.items) >= b.maxSize {
+		b.flushLocked()
+	}
+
+	return nil
+}
+
+// Close flushes the remaining items. Items can no longer be added afterwards.
+func (b *Batcher[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	b.flushLocked()
+}
+
+func (b *Batcher[T]) flushGeneration(generation int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+	b.flushLocked()
+}
+
+func (b *Batcher[T]) flushLocked() {
+	b.generation++
+	if b.stopTimer != nil {
+		b.stopTimer()
+		b.stopTimer = nil
+	}
+	if len(b.items) == 0 {
+		return
+	}
+
+	items := b.items
+	b.items = nil
+	b.flush(items)
+}
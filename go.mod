@@ -1,6 +1,6 @@
 module goutils
 
-go 1.21.3
+go 1.23
 
 require (
 	github.com/andygrunwald/go-jira v1.16.0
@@ -0,0 +1,59 @@
+package iterators
+
+import (
+	"goutils/slices"
+	"iter"
+)
+
+// Map lazily maps each element of the sequence. fn is only called when the element is consumed.
+func Map[T1, T2 any](seq iter.Seq[T1], fn func(T1) T2) iter.Seq[T2] {
+	return func(yield func(T2) bool) {
+		for item := range seq {
+			if !yield(fn(item)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter lazily keeps the elements of the sequence that meet predicate.
+func Filter[T any](seq iter.Seq[T], predicate func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range seq {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Take lazily yields at most the first n elements of the sequence. The underlying sequence is not consumed past them.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		count := 0
+		for item := range seq {
+			if !yield(item) {
+				return
+			}
+			count++
+			if count == n {
+				return
+			}
+		}
+	}
+}
+
+// Collect consumes the sequence and returns its elements as a slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	return slices.Collect(seq)
+}
+
+// ForEach consumes the sequence, applying a side-effect on each element.
+func ForEach[T any](seq iter.Seq[T], fn func(T)) {
+	for item := range seq {
+		fn(item)
+	}
+}
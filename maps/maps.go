@@ -1,5 +1,12 @@
 package maps
 
+import (
+	"goutils/maths"
+	"iter"
+	"sort"
+	"strings"
+)
+
 func Equal[K, V comparable](a, b map[K]V) bool {
 	if len(a) != len(b) {
 		return false
@@ -14,6 +21,29 @@ func Equal[K, V comparable](a, b map[K]V) bool {
 	return true
 }
 
+// Diff3 compares two maps, returning the entries only in a, the entries only in b, and for keys in both whose values
+// differ according to eq, both values as [value in a, value in b]. All three maps are empty if a and b are equal.
+func Diff3[K comparable, V any](a, b map[K]V, eq func(v, w V) bool) (onlyA, onlyB map[K]V, changed map[K][2]V) {
+	onlyA = map[K]V{}
+	onlyB = map[K]V{}
+	changed = map[K][2]V{}
+	for k, v := range a {
+		w, ok := b[k]
+		if !ok {
+			onlyA[k] = v
+		} else if !eq(v, w) {
+			changed[k] = [2]V{v, w}
+		}
+	}
+	for k, w := range b {
+		if _, ok := a[k]; !ok {
+			onlyB[k] = w
+		}
+	}
+
+	return onlyA, onlyB, changed
+}
+
 func Copy[K comparable, V any](m map[K]V) map[K]V {
 	result := make(map[K]V, len(m))
 	for k, v := range m {
@@ -23,6 +53,17 @@ func Copy[K comparable, V any](m map[K]V) map[K]V {
 	return result
 }
 
+// Tap calls fn with the map and returns the map unchanged, e.g. to log it in the middle of a chain of calls.
+func Tap[K comparable, V any](m map[K]V, fn func(map[K]V)) map[K]V {
+	fn(m)
+	return m
+}
+
+// Clear removes all entries from the map in place, so it can be reused.
+func Clear[K comparable, V any](m map[K]V) {
+	clear(m)
+}
+
 // Map maps each key-value pair of the original map to a new key-value pair of a new map.
 func Map[K1, K2 comparable, V1, V2 any](m map[K1]V1, fn func(k K1, v V1) (K2, V2)) map[K2]V2 {
 	result := make(map[K2]V2, len(m))
@@ -34,6 +75,19 @@ func Map[K1, K2 comparable, V1, V2 any](m map[K1]V1, fn func(k K1, v V1) (K2, V2
 	return result
 }
 
+// Transform maps each key-value pair of the original map to a new key-value pair of a new map, keeping only the pairs for which fn returns true.
+// If several pairs map to the same key, which one is kept is unspecified, as with Map.
+func Transform[K1, K2 comparable, V1, V2 any](m map[K1]V1, fn func(k K1, v V1) (K2, V2, bool)) map[K2]V2 {
+	result := map[K2]V2{}
+	for k1, v1 := range m {
+		if k2, v2, ok := fn(k1, v1); ok {
+			result[k2] = v2
+		}
+	}
+
+	return result
+}
+
 // Merge merges two maps. If a key in the first map is present in the second, the value from the first is used.
 func Merge[K comparable, V any](m1, m2 map[K]V) map[K]V {
 	result := make(map[K]V, len(m1)+len(m2))
@@ -48,6 +102,110 @@ func Merge[K comparable, V any](m1, m2 map[K]V) map[K]V {
 	return result
 }
 
+// MergeAll merges any number of maps, applying them in order: if a key is present in several maps, the value from the last one is used.
+// Note that this is the opposite precedence of Merge, so that a list of overrides can be applied on top of defaults.
+func MergeAll[K comparable, V any](maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			result[k] = v
+		}
+	}
+
+	return result
+}
+
+// Flatten flattens a nested map into a single level map whose keys are the outer and inner keys joined by sep.
+// If several entries produce the same joined key (e.g. "a.b"+"c" and "a"+"b.c"), the one that comes last
+// when sorting by outer key and then inner key is used.
+func Flatten[V any](m map[string]map[string]V, sep string) map[string]V {
+	result := map[string]V{}
+	outerKeys := Keys(m)
+	sort.Strings(outerKeys)
+	for _, outer := range outerKeys {
+		innerKeys := Keys(m[outer])
+		sort.Strings(innerKeys)
+		for _, inner := range innerKeys {
+			result[outer+sep+inner] = m[outer][inner]
+		}
+	}
+
+	return result
+}
+
+// Unflatten reverses Flatten by splitting each key at the first occurrence of sep.
+// Keys that don't contain sep are stored under themselves as the outer key with an empty inner key.
+func Unflatten[V any](m map[string]V, sep string) map[string]map[string]V {
+	result := map[string]map[string]V{}
+	for k, v := range m {
+		outer, inner, _ := strings.Cut(k, sep)
+		if _, ok := result[outer]; !ok {
+			result[outer] = map[string]V{}
+		}
+		result[outer][inner] = v
+	}
+
+	return result
+}
+
+// Partition splits a map into the entries that meet predicate and the entries that don't.
+// Both returned maps are non-nil, even if empty.
+func Partition[K comparable, V any](m map[K]V, predicate func(k K, v V) bool) (matched, unmatched map[K]V) {
+	matched = map[K]V{}
+	unmatched = map[K]V{}
+	for k, v := range m {
+		if predicate(k, v) {
+			matched[k] = v
+		} else {
+			unmatched[k] = v
+		}
+	}
+
+	return matched, unmatched
+}
+
+// Count returns the number of entries in the map.
+func Count[K comparable, V any](m map[K]V) int {
+	return len(m)
+}
+
+// IsEmpty verifies if a map has no entry. Nil and empty maps are treated the same.
+func IsEmpty[K comparable, V any](m map[K]V) bool {
+	return len(m) == 0
+}
+
+// IsNotEmpty verifies if a map has at least one entry.
+func IsNotEmpty[K comparable, V any](m map[K]V) bool {
+	return len(m) > 0
+}
+
+// CountIf returns the number of entries in the map that meet predicate.
+func CountIf[K comparable, V any](m map[K]V, predicate func(k K, v V) bool) int {
+	count := 0
+	for k, v := range m {
+		if predicate(k, v) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// SumBy groups the items by the chosen keys and sums the value extracted from each item per key, in a single pass.
+func SumBy[T any, K comparable, N maths.Number](items []T, keyFn func(item T) K, valFn func(item T) N) map[K]N {
+	result := map[K]N{}
+	for _, item := range items {
+		result[keyFn(item)] += valFn(item)
+	}
+
+	return result
+}
+
 func ToSlice[K comparable, V, T any](m map[K]V, fn func(k K, v V) T) []T {
 	result := make([]T, 0, len(m))
 	for k, v := range m {
@@ -57,6 +215,16 @@ func ToSlice[K comparable, V, T any](m map[K]V, fn func(k K, v V) T) []T {
 	return result
 }
 
+// ToSortedSlice projects each key-value pair of the map and returns the results sorted by the less function.
+func ToSortedSlice[K comparable, V, T any](m map[K]V, transform func(k K, v V) T, less func(a, b T) bool) []T {
+	result := ToSlice(m, transform)
+	sort.Slice(result, func(i, j int) bool {
+		return less(result[i], result[j])
+	})
+
+	return result
+}
+
 func Keys[K comparable, V any](m map[K]V) []K {
 	return ToSlice(m, func(k K, _ V) K { return k })
 }
@@ -64,3 +232,50 @@ func Keys[K comparable, V any](m map[K]V) []K {
 func Values[K comparable, V any](m map[K]V) []V {
 	return ToSlice(m, func(_ K, v V) V { return v })
 }
+
+// All returns a sequence over the key-value pairs of the map, e.g. for range-over-func loops.
+// Like ranging over the map directly, the iteration order is not specified.
+func All[K comparable, V any](m map[K]V) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// DefaultMap is a map that creates and stores a default value the first time a missing key is accessed.
+type DefaultMap[K comparable, V any] struct {
+	m       map[K]V
+	factory func() V
+}
+
+// NewDefaultMap creates an empty DefaultMap that uses factory to create the value of a missing key.
+func NewDefaultMap[K comparable, V any](factory func() V) *DefaultMap[K, V] {
+	return &DefaultMap[K, V]{
+		m:       map[K]V{},
+		factory: factory,
+	}
+}
+
+// Get returns the value of the key. If the key is missing, a value is created with the factory and stored first.
+func (d *DefaultMap[K, V]) Get(k K) V {
+	v, ok := d.m[k]
+	if !ok {
+		v = d.factory()
+		d.m[k] = v
+	}
+
+	return v
+}
+
+// Set stores the value of the key.
+func (d *DefaultMap[K, V]) Set(k K, v V) {
+	d.m[k] = v
+}
+
+// Map returns the underlying map. It is not copied, so changes to it are visible in the DefaultMap.
+func (d *DefaultMap[K, V]) Map() map[K]V {
+	return d.m
+}
@@ -1,9 +1,23 @@
 package maths
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unsafe"
+
 	"golang.org/x/exp/constraints"
 )
 
+// Number is any integer or floating-point type.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// ErrOverflow is returned when the result of an integer operation doesn't fit in the target type.
+var ErrOverflow = errors.New("integer overflow")
+
 func Min[T constraints.Ordered](a, b T) T {
 	if a < b {
 		return a
@@ -29,3 +43,250 @@ func GreaterThan[T constraints.Ordered](a, b T) bool {
 func IsWithinRange[T constraints.Ordered](val, lowerBound, upperBound T) bool {
 	return val >= lowerBound && val <= upperBound
 }
+
+// NarrowCheck converts an integer to another integer type, returning an error wrapping ErrOverflow
+// instead of silently truncating when the value doesn't fit in the target type.
+func NarrowCheck[From, To constraints.Integer](v From) (To, error) {
+	result := To(v)
+	if From(result) != v || (v < 0) != (result < 0) {
+		return 0, fmt.Errorf("%w: %v does not fit in %T", ErrOverflow, v, result)
+	}
+	return result, nil
+}
+
+func ToInt32Checked(v int64) (int32, error) {
+	return NarrowCheck[int64, int32](v)
+}
+
+// AddChecked returns a + b, or an error wrapping ErrOverflow if the sum doesn't fit in T.
+func AddChecked[T constraints.Integer](a, b T) (T, error) {
+	lower, upper := integerBounds[T]()
+	if (b > 0 && a > upper-b) || (b < 0 && a < lower-b) {
+		return 0, fmt.Errorf("%w: %v + %v", ErrOverflow, a, b)
+	}
+	return a + b, nil
+}
+
+// SubChecked returns a - b, or an error wrapping ErrOverflow if the difference doesn't fit in T.
+func SubChecked[T constraints.Integer](a, b T) (T, error) {
+	lower, upper := integerBounds[T]()
+	if (b < 0 && a > upper+b) || (b > 0 && a < lower+b) {
+		return 0, fmt.Errorf("%w: %v - %v", ErrOverflow, a, b)
+	}
+	return a - b, nil
+}
+
+// MulChecked returns a * b, or an error wrapping ErrOverflow if the product doesn't fit in T.
+func MulChecked[T constraints.Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+
+	lower, upper := integerBounds[T]()
+	var overflow bool
+	switch {
+	case a > 0 && b > 0:
+		overflow = a > upper/b
+	case a > 0:
+		overflow = b < lower/a
+	case b > 0:
+		overflow = a < lower/b
+	default:
+		overflow = b < upper/a
+	}
+	if overflow {
+		return 0, fmt.Errorf("%w: %v * %v", ErrOverflow, a, b)
+	}
+	return a * b, nil
+}
+
+// integerBounds returns the minimum and maximum values of an integer type.
+func integerBounds[T constraints.Integer]() (T, T) {
+	upper := ^T(0)
+	if upper > 0 {
+		// unsigned: all bits set is the maximum
+		return 0, upper
+	}
+
+	// signed: 1 shifted into the sign bit wraps to the minimum, and the minimum minus one wraps to the maximum
+	lower := T(1) << (unsafe.Sizeof(upper)*8 - 1)
+	return lower, lower - 1
+}
+
+// Percentile returns the p-th percentile of the values, using linear interpolation between the closest ranks.
+// p is clamped to the range [0, 100], and NaN is returned for empty values. The values are sorted in a copy, so the input is not modified.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := Max(0, Min(p, 100)) / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Hypot returns sqrt(a*a + b*b), avoiding overflow and underflow like math.Hypot.
+func Hypot(a, b float64) float64 {
+	return math.Hypot(a, b)
+}
+
+// Distance returns the Euclidean distance between the points (x1, y1) and (x2, y2).
+func Distance(x1, y1, x2, y2 float64) float64 {
+	return Hypot(x2-x1, y2-y1)
+}
+
+// ManhattanDistance returns the sum of the absolute differences between the coordinates of the points (x1, y1) and (x2, y2).
+func ManhattanDistance(x1, y1, x2, y2 float64) float64 {
+	return math.Abs(x2-x1) + math.Abs(y2-y1)
+}
+
+// RoundToPlaces rounds the value half away from zero to the given number of decimal places.
+// Negative places round to the left of the decimal point, e.g. -3 rounds to the nearest thousand.
+func RoundToPlaces(value float64, places int) float64 {
+	if places < 0 {
+		factor := math.Pow(10, float64(-places))
+		return math.Round(value/factor) * factor
+	}
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// RoundToSignificant rounds the value half away from zero to the given number of significant digits, e.g. 1234 to 1200 with 2 digits.
+// Zero, NaN and infinities are returned unchanged, and fewer than 1 digit is treated as 1.
+func RoundToSignificant(value float64, digits int) float64 {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+
+	magnitude := int(math.Floor(math.Log10(math.Abs(value))))
+	return RoundToPlaces(value, Max(digits, 1)-1-magnitude)
+}
+
+// SafeDivide returns a / b, or fallback if b is zero. For floats, fallback is also returned when the result would be infinite or NaN.
+func SafeDivide[T Number](a, b, fallback T) T {
+	if b == 0 {
+		return fallback
+	}
+
+	result := a / b
+	if f := float64(result); math.IsInf(f, 0) || math.IsNaN(f) {
+		return fallback
+	}
+	return result
+}
+
+// CumulativeSum returns the running totals of the values, i.e. each element is the sum of the values up to and including that index.
+func CumulativeSum[T Number](values []T) []T {
+	result := make([]T, 0, len(values))
+	var sum T
+	for _, v := range values {
+		sum += v
+		result = append(result, sum)
+	}
+	return result
+}
+
+// Mode returns the most frequent value along with its count. Ties are broken in favor of the value that appears first.
+// If values is empty, return zero value, 0 and false.
+func Mode[T comparable](values []T) (T, int, bool) {
+	var mode T
+	if len(values) == 0 {
+		return mode, 0, false
+	}
+
+	counts := make(map[T]int, len(values))
+	for _, v := range values {
+		counts[v]++
+	}
+	maxCount := 0
+	for _, v := range values {
+		if counts[v] > maxCount {
+			mode, maxCount = v, counts[v]
+		}
+	}
+	return mode, maxCount, true
+}
+
+// MedianInt returns the median of integer values, averaging the two middle values when the length is even.
+// NaN is returned for empty values. The values are sorted in a copy, so the input is not modified.
+func MedianInt[T constraints.Integer](values []T) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	sorted := make([]T, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return (float64(sorted[middle-1]) + float64(sorted[middle])) / 2
+}
+
+// GCD returns the greatest common divisor of a and b, which is never negative. GCD(0, 0) is 0.
+func GCD[T constraints.Integer](a, b T) T {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// LCM returns the least common multiple of a and b, which is never negative. It is 0 if either value is 0.
+func LCM[T constraints.Integer](a, b T) T {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	result := a / GCD(a, b) * b
+	if result < 0 {
+		return -result
+	}
+	return result
+}
+
+// GCDAll returns the greatest common divisor of all the values. Zeros don't change the result, and it is 0 if there are no values.
+func GCDAll[T constraints.Integer](values ...T) T {
+	var result T
+	for _, v := range values {
+		result = GCD(result, v)
+	}
+	return result
+}
+
+// LCMAll returns the least common multiple of all the values. It is 0 if any value is 0, and 1 if there are no values.
+func LCMAll[T constraints.Integer](values ...T) T {
+	result := T(1)
+	for _, v := range values {
+		result = LCM(result, v)
+	}
+	return result
+}
+
+// WeightedMean returns the average of the values weighted by the weights at the same index.
+// It returns an error if the lengths differ or the total weight is zero.
+func WeightedMean(values, weights []float64) (float64, error) {
+	if len(values) != len(weights) {
+		return 0, fmt.Errorf("values and weights have different lengths: %d and %d", len(values), len(weights))
+	}
+
+	sum, totalWeight := 0.0, 0.0
+	for i, v := range values {
+		sum += v * weights[i]
+		totalWeight += weights[i]
+	}
+	if totalWeight == 0 {
+		return 0, errors.New("the total weight is zero")
+	}
+	return sum / totalWeight, nil
+}
@@ -1,9 +1,15 @@
 package panicrecovery
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"runtime"
 	"runtime/debug"
+	"strings"
+	"sync"
 
 	"go.uber.org/zap"
 )
@@ -25,6 +31,106 @@ func RecoverAndLogWithCleanup(cleanup func()) {
 	}
 }
 
+// For functions with named (T, error) returns. Defer it directly like RecoverAndLog, passing pointers to the named returns:
+// on panic, the result is reset to its zero value and the error is set to one describing the panic.
+func RecoverAndReturn[T any](result *T, errPtr *error) {
+	if err := recover(); err != nil {
+		log(err)
+		var zero T
+		*result = zero
+		*errPtr = fmt.Errorf("recovered from panic: %v", err)
+	}
+}
+
+// ErrTimeout is returned by SafeCallWithTimeout when the context expires before the function returns.
+var ErrTimeout = errors.New("function did not return before the context expired")
+
+// Executes a function and converts a panic into an error. The panic is logged the same way as in RecoverAndLog.
+func SafeCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log(r)
+			err = fmt.Errorf("recovered from panic: %v", r)
+		}
+	}()
+
+	return fn()
+}
+
+// Similar to SafeCall, but runs the function in a new go-routine and returns an error wrapping ErrTimeout if ctx expires first.
+// The function cannot be forcibly stopped: its go-routine keeps running (and leaks) until it returns,
+// so the function should honor the same context and return as soon as it is done.
+func SafeCallWithTimeout(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1) // buffered so the go-routine can always exit once fn returns
+	go func() {
+		done <- SafeCall(fn)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrTimeout, ctx.Err())
+	}
+}
+
+// Like errgroup.Group, but each function runs through SafeCall so that a panic in one go-routine is returned
+// as an error by Wait instead of crashing the process. The zero value is ready to use.
+type Group struct {
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+// Runs the function in a new go-routine, recovering from any panic.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := SafeCall(fn); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+			})
+		}
+	}()
+}
+
+// Blocks until all functions have returned, then returns the first error or recovered panic, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+// Returns the parsed frames of the current go-routine's stack, starting from the caller of CaptureStack.
+// skip is the number of additional frames to skip. Frames of the Go runtime (e.g. the panic machinery) and of this package
+// (e.g. the recovery functions) are left out, so that the frames can be formatted or sent to error tracking consistently.
+func CaptureStack(skip int) []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	for {
+		// skip runtime.Callers and CaptureStack itself
+		n := runtime.Callers(skip+2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+
+	result := []runtime.Frame{}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "runtime.") && !strings.HasPrefix(frame.Function, packagePath+".") {
+			result = append(result, frame)
+		}
+		if !more {
+			return result
+		}
+	}
+}
+
+var packagePath = reflect.TypeOf(Group{}).PkgPath()
+
 func log(err interface{}) {
 	stack := debug.Stack()
 	fmt.Fprintf(os.Stderr, "panic: %v\n%s", err, stack)
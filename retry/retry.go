@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"goutils/maths"
+	"math/rand"
+	"time"
+)
+
+// Config configures the attempts made by Do. The zero value makes a single attempt.
+type Config struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 1 are treated as 1.
+	MaxAttempts int
+	// InitialDelay is the delay before the second attempt.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between attempts. Zero means no cap.
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each attempt. Values below 1 are treated as 2.
+	Multiplier float64
+	// Jitter is the fraction of each delay, between 0 and 1, that is randomly subtracted from it to spread out retries.
+	Jitter float64
+	// Retryable decides whether an error is worth retrying. If nil, all errors are retried.
+	Retryable func(err error) bool
+	// Sleep waits for the delay or until the context is done. If nil, a timer is used. It is meant to be replaced in tests.
+	Sleep func(ctx context.Context, d time.Duration) error
+	// Rand is the source of the jitter. If nil, the global source of math/rand is used.
+	// A *rand.Rand is not safe for concurrent use, so don't share one between concurrent calls of Do.
+	Rand *rand.Rand
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, the attempts are exhausted, or ctx is done.
+// A non-retryable error is returned as is. Otherwise, the last error of fn is wrapped along with the reason for giving up.
+func Do(ctx context.Context, cfg Config, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	maxAttempts := maths.Max(cfg.MaxAttempts, 1)
+	multiplier := cfg.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	sleep := cfg.Sleep
+	if sleep == nil {
+		sleep = sleepWithContext
+	}
+
+	delay := cfg.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if cfg.Retryable != nil && !cfg.Retryable(err) {
+			return err
+		}
+		if attempt >= maxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		if ctxErr := sleep(ctx, withJitter(delay, cfg.Jitter, cfg.Rand)); ctxErr != nil {
+			return fmt.Errorf("%w after %d attempts: %w", ctxErr, attempt, err)
+		}
+
+		delay = time.Duration(float64(delay) * multiplier)
+		if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+			delay = cfg.MaxDelay
+		}
+	}
+}
+
+func withJitter(delay time.Duration, jitter float64, r *rand.Rand) time.Duration {
+	jitter = maths.Min(maths.Max(jitter, 0), 1)
+	if jitter == 0 {
+		return delay
+	}
+
+	random := rand.Float64
+	if r != nil {
+		random = r.Float64
+	}
+	return delay - time.Duration(float64(delay)*jitter*random())
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
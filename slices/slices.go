@@ -1,7 +1,15 @@
 package slices
 
 import (
+	"errors"
+	"fmt"
 	"goutils/maths"
+	"iter"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
 
 	"golang.org/x/exp/constraints"
 )
@@ -14,6 +22,27 @@ func NilOr[T any](items []T) []T {
 	return items
 }
 
+// IsEmpty verifies if a slice has no element. Nil and empty slices are treated the same.
+func IsEmpty[T any](items []T) bool {
+	return len(items) == 0
+}
+
+// IsNotEmpty verifies if a slice has at least one element.
+func IsNotEmpty[T any](items []T) bool {
+	return len(items) > 0
+}
+
+// CoalesceSlices returns the first non-empty slice, or an empty slice if all of them are empty.
+func CoalesceSlices[T any](slices ...[]T) []T {
+	for _, items := range slices {
+		if len(items) > 0 {
+			return items
+		}
+	}
+
+	return []T{}
+}
+
 // Contains verifies if a slice contains the target element.
 func Contains[T comparable](items []T, target T) bool {
 	for _, v := range items {
@@ -25,6 +54,92 @@ func Contains[T comparable](items []T, target T) bool {
 	return false
 }
 
+// EqualWithin verifies if two slices have the same length and each pair of elements is within tolerance:
+// |a-b| <= max(absTol, relTol*max(|a|, |b|)). A NaN only matches another NaN and infinities only match
+// infinities of the same sign, so that golden values containing them can still be compared.
+func EqualWithin[T constraints.Float](a, b []T, absTol, relTol T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		if x == y || (math.IsNaN(x) && math.IsNaN(y)) {
+			continue
+		}
+		if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+			return false
+		}
+		if math.Abs(x-y) > math.Max(float64(absTol), float64(relTol)*math.Max(math.Abs(x), math.Abs(y))) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualUnordered verifies if two slices contain the same elements with the same multiplicities, regardless of order.
+func EqualUnordered[T comparable](a, b []T) bool {
+	return EqualUnorderedBy(a, b, func(item T) T { return item })
+}
+
+// EqualUnorderedBy verifies if two slices contain elements with the same keys with the same multiplicities, regardless of order.
+func EqualUnorderedBy[T any, K comparable](a, b []T, keyFn func(T) K) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := make(map[K]int, len(a))
+	for _, item := range a {
+		counts[keyFn(item)]++
+	}
+	for _, item := range b {
+		key := keyFn(item)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+
+	return true
+}
+
+// CommonPrefix returns the longest leading run of elements shared by both slices, as a new slice.
+func CommonPrefix[T comparable](a, b []T) []T {
+	length := 0
+	for length < len(a) && length < len(b) && a[length] == b[length] {
+		length++
+	}
+
+	return Copy(a[:length])
+}
+
+// HasPrefix verifies if a slice starts with all the elements of prefix. Every slice starts with an empty prefix.
+func HasPrefix[T comparable](items, prefix []T) bool {
+	if len(prefix) > len(items) {
+		return false
+	}
+	for i := range prefix {
+		if items[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ContainsBy verifies if a slice contains an element that meets predicate. It is the same as Any.
+func ContainsBy[T any](items []T, predicate func(T) bool) bool {
+	return Any(items, predicate)
+}
+
+// ContainsElementBy verifies if a slice contains the target element using a custom equality function.
+func ContainsElementBy[T any](items []T, target T, eq func(a, b T) bool) bool {
+	return Any(items, func(item T) bool {
+		return eq(item, target)
+	})
+}
+
 // NonZeroValues returns a new slice with only non-zero values (e.g. non-nil pointers, non-empty strings, etc.) by preserving the original order.
 func NonZeroValues[T comparable](items []T) []T {
 	var zero T // nil for pointers, 0 for int, "" for string, etc.
@@ -33,6 +148,29 @@ func NonZeroValues[T comparable](items []T) []T {
 	})
 }
 
+// ReplaceAll returns a new slice with every occurrence of oldValue replaced by newValue. The ordering and length are preserved.
+func ReplaceAll[T comparable](items []T, oldValue, newValue T) []T {
+	return Map(items, func(item T) T {
+		if item == oldValue {
+			return newValue
+		}
+		return item
+	})
+}
+
+// ReplaceFirst returns a new slice with the first occurrence of oldValue replaced by newValue. The ordering and length are preserved.
+func ReplaceFirst[T comparable](items []T, oldValue, newValue T) []T {
+	result := Copy(items)
+	for i := range result {
+		if result[i] == oldValue {
+			result[i] = newValue
+			break
+		}
+	}
+
+	return result
+}
+
 // Max returns an item with the maximum value.
 func Max[T constraints.Ordered](items []T) T {
 	return extremum(items, maths.GreaterThan[T])
@@ -43,6 +181,26 @@ func Min[T constraints.Ordered](items []T) T {
 	return extremum(items, maths.LessThan[T])
 }
 
+// MinMax returns the items with the minimum and maximum values in a single pass.
+// If the slice is empty, return zero values and false.
+func MinMax[T constraints.Ordered](items []T) (min, max T, ok bool) {
+	if len(items) == 0 {
+		return min, max, false
+	}
+
+	min, max = items[0], items[0]
+	for _, item := range items[1:] {
+		if item < min {
+			min = item
+		}
+		if item > max {
+			max = item
+		}
+	}
+
+	return min, max, true
+}
+
 // extremum returns an slice item that meets the predicate accumulatively.
 // If the slice is empty, return zero value.
 func extremum[T constraints.Ordered](items []T, predicate func(T, T) bool) T {
@@ -74,6 +232,66 @@ func Unique[T comparable](items []T) []T {
 	return result
 }
 
+// Difference returns the unique elements of a that are not in b, in the order of their first appearance in a.
+func Difference[T comparable](a, b []T) []T {
+	exclude := toSet(b)
+	return Unique(Filter(a, func(item T) bool {
+		_, ok := exclude[item]
+		return !ok
+	}))
+}
+
+// Intersection returns the unique elements of a that are also in b, in the order of their first appearance in a.
+func Intersection[T comparable](a, b []T) []T {
+	include := toSet(b)
+	return Unique(Filter(a, func(item T) bool {
+		_, ok := include[item]
+		return ok
+	}))
+}
+
+// Union returns the unique elements of a followed by the unique elements of b that are not in a, in the order of their first appearance.
+func Union[T comparable](a, b []T) []T {
+	result := make([]T, 0, len(a)+len(b))
+	result = append(result, a...)
+	return Unique(append(result, b...))
+}
+
+func toSet[T comparable](items []T) map[T]struct{} {
+	result := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		result[item] = struct{}{}
+	}
+
+	return result
+}
+
+// FirstDuplicate returns the first value that repeats an earlier one in the slice.
+// If there are no duplicates, return zero value and false.
+func FirstDuplicate[T comparable](items []T) (T, bool) {
+	seen := make(map[T]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := seen[item]; ok {
+			return item, true
+		}
+		seen[item] = struct{}{}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// HasDuplicates checks if any value appears more than once in the slice. It stops at the first repeat.
+func HasDuplicates[T comparable](items []T) bool {
+	_, ok := FirstDuplicate(items)
+	return ok
+}
+
+// AllUnique checks if every value in the slice appears only once. An empty slice is considered unique.
+func AllUnique[T comparable](items []T) bool {
+	return !HasDuplicates(items)
+}
+
 func Map[T1, T2 any](items []T1, fn func(T1) T2) []T2 {
 	result := make([]T2, 0, len(items))
 	for _, item := range items {
@@ -83,6 +301,133 @@ func Map[T1, T2 any](items []T1, fn func(T1) T2) []T2 {
 	return result
 }
 
+// JoinToString converts each element to a string with fn and joins the results with sep.
+func JoinToString[T any](items []T, sep string, fn func(T) string) string {
+	return strings.Join(Map(items, fn), sep)
+}
+
+// Column extracts a column from a slice of structs. It is the same as Map, but named for columnar exports.
+func Column[T, C any](items []T, fn func(T) C) []C {
+	return Map(items, fn)
+}
+
+// Columns extracts several columns of the same type at once, one per selector, in a single pass over the slice.
+// The result has one column per selector, e.g. to write a slice of structs as CSV columns of strings.
+func Columns[T, C any](items []T, selectors ...func(T) C) [][]C {
+	result := make([][]C, 0, len(selectors))
+	for range selectors {
+		result = append(result, make([]C, 0, len(items)))
+	}
+	for _, item := range items {
+		for i, selector := range selectors {
+			result[i] = append(result[i], selector(item))
+		}
+	}
+
+	return result
+}
+
+// ZipWith combines the elements at the same index of both slices with fn. The result stops at the length of the shorter slice.
+func ZipWith[A, B, C any](a []A, b []B, fn func(A, B) C) []C {
+	length := maths.Min(len(a), len(b))
+	result := make([]C, 0, length)
+	for i := 0; i < length; i++ {
+		result = append(result, fn(a[i], b[i]))
+	}
+
+	return result
+}
+
+// Zip pairs the elements at the same index of both slices. It stops at the length of the shorter slice,
+// ignoring the remaining elements of the longer one.
+func Zip[T, U any](a []T, b []U) []Pair[T, U] {
+	return ZipWith(a, b, func(x T, y U) Pair[T, U] {
+		return Pair[T, U]{First: x, Second: y}
+	})
+}
+
+// Unzip splits pairs into a slice of their first values and a slice of their second values.
+// It reverses Zip: Unzip(Zip(a, b)) returns a and b truncated to the shorter length.
+func Unzip[T, U any](pairs []Pair[T, U]) ([]T, []U) {
+	first := make([]T, 0, len(pairs))
+	second := make([]U, 0, len(pairs))
+	for _, pair := range pairs {
+		first = append(first, pair.First)
+		second = append(second, pair.Second)
+	}
+
+	return first, second
+}
+
+// MapIndexed is like Map, but fn also receives the index of each element.
+func MapIndexed[T1, T2 any](items []T1, fn func(i int, item T1) T2) []T2 {
+	result := make([]T2, 0, len(items))
+	for i, item := range items {
+		result = append(result, fn(i, item))
+	}
+
+	return result
+}
+
+// Indexed holds an element of a slice along with its index.
+type Indexed[T any] struct {
+	Index int
+	Value T
+}
+
+// Enumerate returns each element of the slice paired with its index.
+func Enumerate[T any](items []T) []Indexed[T] {
+	return MapIndexed(items, func(i int, item T) Indexed[T] {
+		return Indexed[T]{Index: i, Value: item}
+	})
+}
+
+// MapConcurrent is like Map, but runs fn on up to workers go-routines at the same time. The ordering of the results is preserved.
+// If workers is less than 1, a single go-routine is used.
+func MapConcurrent[T1, T2 any](items []T1, workers int, fn func(T1) T2) []T2 {
+	result := make([]T2, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < maths.Min(maths.Max(workers, 1), len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result[i] = fn(items[i])
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return result
+}
+
+// MapWhile is like Map, but stops at the first element for which fn returns false, excluding it from the result.
+func MapWhile[T1, T2 any](items []T1, fn func(T1) (T2, bool)) []T2 {
+	result := make([]T2, 0, len(items))
+	for _, item := range items {
+		mapped, ok := fn(item)
+		if !ok {
+			break
+		}
+		result = append(result, mapped)
+	}
+
+	return result
+}
+
+// Apply overwrites each element of the slice with the result of fn. Unlike Map, it mutates the input and doesn't allocate.
+func Apply[T any](items []T, fn func(T) T) {
+	for i := range items {
+		items[i] = fn(items[i])
+	}
+}
+
 // Filter keeps all items that meet predicate from the slice.
 func Filter[T any](items []T, predicate func(T) bool) []T {
 	result := make([]T, 0, len(items))
@@ -95,6 +440,103 @@ func Filter[T any](items []T, predicate func(T) bool) []T {
 	return result
 }
 
+// Partition splits a slice into the items that meet predicate and the items that don't, in a single pass.
+// The ordering is preserved in both, and like Filter, both are non-nil even if empty.
+func Partition[T any](items []T, predicate func(T) bool) (matched []T, unmatched []T) {
+	matched = make([]T, 0, len(items))
+	unmatched = make([]T, 0, len(items))
+	for _, item := range items {
+		if predicate(item) {
+			matched = append(matched, item)
+		} else {
+			unmatched = append(unmatched, item)
+		}
+	}
+
+	return matched, unmatched
+}
+
+// FilterIndexed is like Filter, but predicate also receives the index of each element.
+func FilterIndexed[T any](items []T, predicate func(i int, item T) bool) []T {
+	result := make([]T, 0, len(items))
+	for i, item := range items {
+		if predicate(i, item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// FilterWithIndices is like Filter, but also returns the original index of each item kept.
+func FilterWithIndices[T any](items []T, predicate func(T) bool) (kept []T, indices []int) {
+	kept = make([]T, 0, len(items))
+	indices = make([]int, 0, len(items))
+	for i, item := range items {
+		if predicate(item) {
+			kept = append(kept, item)
+			indices = append(indices, i)
+		}
+	}
+
+	return kept, indices
+}
+
+// DeleteFunc returns a new slice without the items that meet predicate, along with the number of items removed. The ordering is preserved.
+func DeleteFunc[T any](items []T, predicate func(T) bool) (remaining []T, removed int) {
+	remaining = Filter(items, func(item T) bool {
+		return !predicate(item)
+	})
+
+	return remaining, len(items) - len(remaining)
+}
+
+// Span splits a slice at the first element that doesn't meet predicate, returning the leading run that does and the remainder as new slices.
+func Span[T any](items []T, predicate func(T) bool) (prefix []T, rest []T) {
+	i := prefixLength(items, predicate)
+	return Copy(items[:i]), Copy(items[i:])
+}
+
+// TakeWhile returns a new slice with the leading run of elements that meet predicate. It stops at the first element that doesn't,
+// even if later elements would meet predicate again.
+func TakeWhile[T any](items []T, predicate func(T) bool) []T {
+	return Copy(items[:prefixLength(items, predicate)])
+}
+
+// DropWhile returns a new slice without the leading run of elements that meet predicate, i.e. from the first element that doesn't.
+func DropWhile[T any](items []T, predicate func(T) bool) []T {
+	return Copy(items[prefixLength(items, predicate):])
+}
+
+// prefixLength returns the length of the leading run of elements that meet predicate.
+func prefixLength[T any](items []T, predicate func(T) bool) int {
+	i := 0
+	for i < len(items) && predicate(items[i]) {
+		i++
+	}
+
+	return i
+}
+
+// Take returns a new slice with at most the first n elements. n is clamped to [0, len(items)], so it never panics.
+func Take[T any](items []T, n int) []T {
+	return Copy(items[:maths.Max(0, maths.Min(n, len(items)))])
+}
+
+// Drop returns a new slice with the elements after the first n. n is clamped to [0, len(items)], so it never panics.
+func Drop[T any](items []T, n int) []T {
+	return Copy(items[maths.Max(0, maths.Min(n, len(items))):])
+}
+
+// Compress keeps the items whose corresponding mask value is true.
+// If the lengths differ, the extra elements of the longer one are ignored, as if both were truncated to the shorter length.
+func Compress[T any](items []T, mask []bool) []T {
+	items = items[:maths.Min(len(items), len(mask))]
+	return FilterIndexed(items, func(i int, _ T) bool {
+		return mask[i]
+	})
+}
+
 // ForEach applies a side-effect on each element in the slice.
 func ForEach[T any](items []T, fn func(T)) {
 	for _, item := range items {
@@ -102,6 +544,13 @@ func ForEach[T any](items []T, fn func(T)) {
 	}
 }
 
+// ForEachIndexed is like ForEach, but fn also receives the index of each element.
+func ForEachIndexed[T any](items []T, fn func(i int, item T)) {
+	for i, item := range items {
+		fn(i, item)
+	}
+}
+
 // Any checks if a slice contains any element that meets predicate.
 func Any[T any](items []T, predicate func(T) bool) bool {
 	for _, item := range items {
@@ -112,6 +561,73 @@ func Any[T any](items []T, predicate func(T) bool) bool {
 	return false
 }
 
+// Find returns the first element that meets predicate. If none does, return zero value and false.
+func Find[T any](items []T, predicate func(T) bool) (T, bool) {
+	if i := FindIndex(items, predicate); i >= 0 {
+		return items[i], true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// FindIndex returns the index of the first element that meets predicate, or -1 if none does.
+func FindIndex[T any](items []T, predicate func(T) bool) int {
+	for i, item := range items {
+		if predicate(item) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// FindLast returns the last element that meets predicate. If none does, return zero value and false.
+func FindLast[T any](items []T, predicate func(T) bool) (T, bool) {
+	if i := FindLastIndex(items, predicate); i >= 0 {
+		return items[i], true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// FindLastIndex returns the index of the last element that meets predicate, or -1 if none does.
+func FindLastIndex[T any](items []T, predicate func(T) bool) int {
+	for i := len(items) - 1; i >= 0; i-- {
+		if predicate(items[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// LongestRun returns the length of the longest contiguous run of elements that meet predicate.
+func LongestRun[T any](items []T, predicate func(T) bool) int {
+	longest, current := 0, 0
+	for _, item := range items {
+		if predicate(item) {
+			current++
+			longest = maths.Max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+
+	return longest
+}
+
+// CurrentRun returns the length of the trailing run of elements that meet predicate.
+func CurrentRun[T any](items []T, predicate func(T) bool) int {
+	count := 0
+	for i := len(items) - 1; i >= 0 && predicate(items[i]); i-- {
+		count++
+	}
+
+	return count
+}
+
 // Repeat creates a slice from a value that is inserted N times.
 func Repeat[T any](value T, times int) []T {
 	result := make([]T, 0, times)
@@ -121,6 +637,27 @@ func Repeat[T any](value T, times int) []T {
 	return result
 }
 
+// PadRight returns a new slice with pad appended until it reaches length. If the slice is already at least that long, it is returned as a copy.
+func PadRight[T any](items []T, length int, pad T) []T {
+	result := make([]T, 0, maths.Max(len(items), length))
+	result = append(result, items...)
+	for len(result) < length {
+		result = append(result, pad)
+	}
+
+	return result
+}
+
+// PadLeft returns a new slice with pad prepended until it reaches length. If the slice is already at least that long, it is returned as a copy.
+func PadLeft[T any](items []T, length int, pad T) []T {
+	result := make([]T, 0, maths.Max(len(items), length))
+	for i := len(items); i < length; i++ {
+		result = append(result, pad)
+	}
+
+	return append(result, items...)
+}
+
 // GroupBy groups the elements of a slice by the chosen keys.
 func GroupBy[T any, K comparable](items []T, fn func(item T) K) map[K][]T {
 	result := map[K][]T{}
@@ -132,6 +669,66 @@ func GroupBy[T any, K comparable](items []T, fn func(item T) K) map[K][]T {
 	return result
 }
 
+// GroupByMultiple groups the elements of a slice by the keys returned for each element.
+// An element is added to every group whose key is returned, and elements without any keys are left out.
+func GroupByMultiple[T any, K comparable](items []T, keysFn func(item T) []K) map[K][]T {
+	result := map[K][]T{}
+	for _, item := range items {
+		for _, key := range keysFn(item) {
+			result[key] = append(result[key], item)
+		}
+	}
+
+	return result
+}
+
+// GroupSizes counts the elements of each group, like taking the length of each group returned by GroupBy, without building the groups.
+func GroupSizes[T any, K comparable](items []T, keyFn func(item T) K) map[K]int {
+	result := map[K]int{}
+	for _, item := range items {
+		result[keyFn(item)]++
+	}
+
+	return result
+}
+
+// PartitionBy splits the elements of a slice into buckets by the chosen keys. It is the same as GroupBy.
+func PartitionBy[T any, K comparable](items []T, keyFn func(item T) K) map[K][]T {
+	return GroupBy(items, keyFn)
+}
+
+// PartitionInto splits the elements of a slice into n buckets, indexed by the return value of bucketFn. The ordering is preserved within each bucket.
+// Elements whose bucket index is outside [0, n) are skipped. All n buckets are non-nil, even if empty.
+func PartitionInto[T any](items []T, bucketFn func(item T) int, n int) [][]T {
+	result := make([][]T, 0, maths.Max(n, 0))
+	for i := 0; i < n; i++ {
+		result = append(result, []T{})
+	}
+	for _, item := range items {
+		if bucket := bucketFn(item); isValidIndex(bucket, n) {
+			result[bucket] = append(result[bucket], item)
+		}
+	}
+
+	return result
+}
+
+// PartitionConsecutive splits a slice into runs of consecutive elements, starting a new run whenever sameGroup returns false
+// for an element and the one before it, e.g. to group sorted values that are within some distance of each other.
+// The runs share the underlying array of the slice, but their capacity is limited so appending to one doesn't overwrite the next.
+func PartitionConsecutive[T any](items []T, sameGroup func(prev, curr T) bool) [][]T {
+	result := [][]T{}
+	start := 0
+	for i := 1; i <= len(items); i++ {
+		if i == len(items) || !sameGroup(items[i-1], items[i]) {
+			result = append(result, items[start:i:i])
+			start = i
+		}
+	}
+
+	return result
+}
+
 // ToMap returns a map with the key-value pair generated from each slice element.
 func ToMap[T any, K comparable, V any](items []T, fn func(item T) (K, V)) map[K]V {
 	result := make(map[K]V, len(items))
@@ -148,6 +745,27 @@ func Copy[T any](items []T) []T {
 	return append(result, items...)
 }
 
+// SortBy returns a new slice sorted in ascending order of the keys returned by keyFn.
+// The sort is stable, so elements with equal keys keep their original order. The original slice is not modified.
+func SortBy[T any, K constraints.Ordered](items []T, keyFn func(item T) K) []T {
+	result := Copy(items)
+	sort.SliceStable(result, func(i, j int) bool {
+		return keyFn(result[i]) < keyFn(result[j])
+	})
+
+	return result
+}
+
+// SortByDesc is like SortBy, but sorts in descending order of the keys. Elements with equal keys still keep their original order.
+func SortByDesc[T any, K constraints.Ordered](items []T, keyFn func(item T) K) []T {
+	result := Copy(items)
+	sort.SliceStable(result, func(i, j int) bool {
+		return keyFn(result[i]) > keyFn(result[j])
+	})
+
+	return result
+}
+
 // Reduce reduces a slice to a value by running each element through an accumulator function.
 // Each iteration is supplied the return value of the previous, except for the first iteration, which begins with a user-supplied initial value.
 func Reduce[T any, R any](items []T, initial R, accumulator func(acc R, item T) R) R {
@@ -158,6 +776,63 @@ func Reduce[T any, R any](items []T, initial R, accumulator func(acc R, item T)
 	return initial
 }
 
+// FoldRight reduces a slice to a value like Reduce, but from the last element to the first.
+// The accumulator receives the element first and the accumulated value second, following the right fold convention.
+func FoldRight[T any, R any](items []T, initial R, accumulator func(item T, acc R) R) R {
+	for i := len(items) - 1; i >= 0; i-- {
+		initial = accumulator(items[i], initial)
+	}
+
+	return initial
+}
+
+// TakeWhileAccumulating folds the items into an accumulated value like Reduce, but stops at the first item for which step returns false,
+// e.g. to select files until a size budget is reached. It returns the items taken and the value accumulated from them only.
+func TakeWhileAccumulating[T any, R any](items []T, initial R, step func(acc R, item T) (R, bool)) (selected []T, finalAcc R) {
+	selected = make([]T, 0, len(items))
+	for _, item := range items {
+		acc, ok := step(initial, item)
+		if !ok {
+			break
+		}
+		initial = acc
+		selected = append(selected, item)
+	}
+
+	return selected, initial
+}
+
+// SlidingReduce reduces each sliding window of the given size, starting from the initial value every time, without copying the windows.
+// The result has one value per window, so it is empty if size is not positive or greater than the length of the slice.
+func SlidingReduce[T, R any](items []T, size int, initial R, accumulator func(acc R, item T) R) []R {
+	if size <= 0 || size > len(items) {
+		return []R{}
+	}
+
+	result := make([]R, 0, len(items)-size+1)
+	for i := 0; i+size <= len(items); i++ {
+		result = append(result, Reduce(items[i:i+size], initial, accumulator))
+	}
+
+	return result
+}
+
+// WindowStep returns the windows of the given size, starting every step elements, e.g. size 2 and step 2 over [1,2,3,4] gives [[1,2],[3,4]].
+// Trailing elements that don't fill a whole window are dropped. If size or step is not positive, the result is empty.
+// The windows share the underlying array of the slice, but their capacity is limited so appending to one doesn't overwrite the next.
+func WindowStep[T any](items []T, size, step int) [][]T {
+	if size <= 0 || step <= 0 {
+		return [][]T{}
+	}
+
+	result := [][]T{}
+	for start := 0; start+size <= len(items); start += step {
+		result = append(result, items[start:start+size:start+size])
+	}
+
+	return result
+}
+
 // Flatten returns an array a single level deep
 func Flatten[T any](items [][]T) []T {
 	count := 0
@@ -172,3 +847,473 @@ func Flatten[T any](items [][]T) []T {
 
 	return result
 }
+
+// ChunkIntoN splits a slice into exactly n chunks whose sizes differ by at most one, with the larger chunks first.
+// If n is greater than the length of the slice, the trailing chunks are empty. If n is not positive, the result is empty.
+// The chunks share the underlying array of the slice, but their capacity is limited so appending to one doesn't overwrite the next.
+func ChunkIntoN[T any](items []T, n int) [][]T {
+	if n <= 0 {
+		return [][]T{}
+	}
+
+	result := make([][]T, 0, n)
+	size, remainder := len(items)/n, len(items)%n
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + size
+		if i < remainder {
+			end++
+		}
+		result = append(result, items[start:end:end])
+		start = end
+	}
+
+	return result
+}
+
+// Chunk splits a slice into chunks of the given size. The last chunk is smaller if the length is not a multiple of size.
+// If size is not positive, the result is empty.
+// The chunks share the underlying array of the slice, but their capacity is limited so appending to one doesn't overwrite the next.
+func Chunk[T any](items []T, size int) [][]T {
+	return Map(ChunkRanges(len(items), size), func(r [2]int) []T {
+		return items[r[0]:r[1]:r[1]]
+	})
+}
+
+// ChunkRanges returns the [start, end) index ranges of the chunks Chunk would produce for a slice of the given length,
+// so that callers can take the chunks themselves without allocating them. If size is not positive, the result is empty.
+func ChunkRanges(length, size int) [][2]int {
+	if size <= 0 || length <= 0 {
+		return [][2]int{}
+	}
+
+	result := make([][2]int, 0, (length+size-1)/size)
+	for start := 0; start < length; start += size {
+		result = append(result, [2]int{start, maths.Min(start+size, length)})
+	}
+
+	return result
+}
+
+// ProcessChunks calls fn on each chunk of the given size, in order, and returns all the results flattened into one slice.
+// It stops at the first error, which is returned along with the index of the chunk that failed.
+func ProcessChunks[T, R any](items []T, size int, fn func(chunk []T) ([]R, error)) ([]R, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid chunk size %d: must be positive", size)
+	}
+
+	result := make([]R, 0, len(items))
+	for i, chunk := range Chunk(items, size) {
+		chunkResult, err := fn(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("chunk %d: %w", i, err)
+		}
+		result = append(result, chunkResult...)
+	}
+
+	return result, nil
+}
+
+// StreamChunks pulls elements from next until it returns false, and hands them to handle in chunks of the given size,
+// so that the whole input never needs to be in memory. The last chunk is smaller if the elements don't divide evenly.
+// It stops at the first error returned by handle. Each chunk is a new slice, so handle may keep it.
+func StreamChunks[T any](next func() (T, bool), size int, handle func(chunk []T) error) error {
+	if size <= 0 {
+		return fmt.Errorf("invalid chunk size %d: must be positive", size)
+	}
+
+	chunk := make([]T, 0, size)
+	for {
+		item, ok := next()
+		if ok {
+			chunk = append(chunk, item)
+		}
+		if len(chunk) == size || (!ok && len(chunk) > 0) {
+			if err := handle(chunk); err != nil {
+				return err
+			}
+			chunk = make([]T, 0, size)
+		}
+		if !ok {
+			return nil
+		}
+	}
+}
+
+// DiffKind describes how an element of a DiffOp relates the old slice to the new one.
+type DiffKind int
+
+const (
+	// DiffEqual marks an element present in both slices.
+	DiffEqual DiffKind = iota
+	// DiffInsert marks an element only present in the new slice.
+	DiffInsert
+	// DiffDelete marks an element only present in the old slice.
+	DiffDelete
+)
+
+// DiffOp is a single step of an edit script produced by Diff.
+type DiffOp[T any] struct {
+	Kind  DiffKind
+	Value T
+}
+
+// Diff returns the edit script that transforms oldItems into newItems, computed via the longest common subsequence.
+// When both a deletion and an insertion are possible at the same position, the deletion comes first.
+func Diff[T comparable](oldItems, newItems []T) []DiffOp[T] {
+	n, m := len(oldItems), len(newItems)
+
+	// lcs[i][j] holds the length of the longest common subsequence of oldItems[i:] and newItems[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldItems[i] == newItems[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maths.Max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	result := make([]DiffOp[T], 0, n+m-lcs[0][0])
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case oldItems[i] == newItems[j]:
+			result = append(result, DiffOp[T]{Kind: DiffEqual, Value: oldItems[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, DiffOp[T]{Kind: DiffDelete, Value: oldItems[i]})
+			i++
+		default:
+			result = append(result, DiffOp[T]{Kind: DiffInsert, Value: newItems[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, DiffOp[T]{Kind: DiffDelete, Value: oldItems[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, DiffOp[T]{Kind: DiffInsert, Value: newItems[j]})
+	}
+
+	return result
+}
+
+// Move returns a new slice with the element at index from relocated to index to, shifting the elements in between.
+// Out-of-range indices are clamped to the bounds of the slice, so a negative index refers to the first element
+// and an index past the end refers to the last one. An empty slice is returned as an empty copy.
+func Move[T any](items []T, from, to int) []T {
+	result := Copy(items)
+	if len(result) == 0 {
+		return result
+	}
+
+	from = clampIndex(from, len(result))
+	to = clampIndex(to, len(result))
+	item := result[from]
+	if from < to {
+		copy(result[from:to], result[from+1:to+1])
+	} else {
+		copy(result[to+1:from+1], result[to:from])
+	}
+	result[to] = item
+
+	return result
+}
+
+// clampIndex clamps an index to the range [0, length-1].
+func clampIndex(i, length int) int {
+	return maths.Max(0, maths.Min(i, length-1))
+}
+
+// Splice returns a new slice with deleteCount elements removed at index start and the insert elements added in their place,
+// like JavaScript's Array.splice. start is clamped to [0, len(items)] and deleteCount to the number of elements after start.
+func Splice[T any](items []T, start, deleteCount int, insert ...T) []T {
+	start = maths.Max(0, maths.Min(start, len(items)))
+	deleteCount = maths.Max(0, maths.Min(deleteCount, len(items)-start))
+
+	result := make([]T, 0, len(items)-deleteCount+len(insert))
+	result = append(result, items[:start]...)
+	result = append(result, insert...)
+	return append(result, items[start+deleteCount:]...)
+}
+
+// Swap swaps the elements at indices i and j in place.
+// If either index is out of range, the slice is left untouched and false is returned.
+func Swap[T any](items []T, i, j int) bool {
+	if !isValidIndex(i, len(items)) || !isValidIndex(j, len(items)) {
+		return false
+	}
+
+	items[i], items[j] = items[j], items[i]
+	return true
+}
+
+// At returns the element at index i. Negative indices count from the end of the slice, so -1 is the last element.
+// If the index is out of range, return zero value and false.
+func At[T any](items []T, i int) (T, bool) {
+	if i < 0 {
+		i += len(items)
+	}
+	if !isValidIndex(i, len(items)) {
+		var zero T
+		return zero, false
+	}
+
+	return items[i], true
+}
+
+// GetOr returns the element at index i, or fallback if the index is out of range. Unlike At, negative indices are out of range.
+func GetOr[T any](items []T, i int, fallback T) T {
+	if !isValidIndex(i, len(items)) {
+		return fallback
+	}
+
+	return items[i]
+}
+
+// isValidIndex verifies if an index is within the range [0, length).
+func isValidIndex(i, length int) bool {
+	return i >= 0 && i < length
+}
+
+// Pair holds two values of possibly different types.
+type Pair[T, U any] struct {
+	First  T
+	Second U
+}
+
+// Frequencies returns each distinct value paired with the number of times it appears, sorted by descending count.
+// Values with the same count keep the order of their first appearance in the slice.
+func Frequencies[T comparable](items []T) []Pair[T, int] {
+	indexByValue := map[T]int{}
+	result := []Pair[T, int]{}
+	for _, item := range items {
+		if i, ok := indexByValue[item]; ok {
+			result[i].Second++
+			continue
+		}
+		indexByValue[item] = len(result)
+		result = append(result, Pair[T, int]{First: item, Second: 1})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Second > result[j].Second
+	})
+
+	return result
+}
+
+// CartesianProduct returns every pairing of an element of a with an element of b, in a-major order:
+// all pairs with a[0] come first, in the order of b, then all pairs with a[1], and so on.
+func CartesianProduct[A, B any](a []A, b []B) []Pair[A, B] {
+	result := make([]Pair[A, B], 0, len(a)*len(b))
+	for _, x := range a {
+		for _, y := range b {
+			result = append(result, Pair[A, B]{First: x, Second: y})
+		}
+	}
+
+	return result
+}
+
+// Combinations returns all combinations of k elements, keeping the order of the elements within each combination.
+// There are n!/(k!(n-k)!) of them, which grows very quickly, so only use it on small inputs.
+// If k is 0 the result holds a single empty combination, and if k is negative or greater than the length the result is empty.
+func Combinations[T any](items []T, k int) [][]T {
+	result := [][]T{}
+	if k < 0 || k > len(items) {
+		return result
+	}
+
+	indexes := make([]int, k)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	for {
+		result = append(result, Map(indexes, func(i int) T { return items[i] }))
+
+		// advance the rightmost index that can still move right, and reset the ones after it
+		i := k - 1
+		for i >= 0 && indexes[i] == len(items)-k+i {
+			i--
+		}
+		if i < 0 {
+			return result
+		}
+		indexes[i]++
+		for j := i + 1; j < k; j++ {
+			indexes[j] = indexes[j-1] + 1
+		}
+	}
+}
+
+// Permutations returns all orderings of the elements, in lexicographic order of their original indexes.
+// There are n! of them, which grows very quickly, so only use it on small inputs. An empty slice has a single empty permutation.
+func Permutations[T any](items []T) [][]T {
+	result := [][]T{}
+	current := make([]T, 0, len(items))
+	used := make([]bool, len(items))
+	var permute func()
+	permute = func() {
+		if len(current) == len(items) {
+			result = append(result, Copy(current))
+			return
+		}
+		for i, item := range items {
+			if used[i] {
+				continue
+			}
+			used[i] = true
+			current = append(current, item)
+			permute()
+			current = current[:len(current)-1]
+			used[i] = false
+		}
+	}
+	permute()
+
+	return result
+}
+
+// Values returns a sequence over the elements of the slice, e.g. for range-over-func loops.
+func Values[T any](items []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// All returns a sequence over the index-element pairs of the slice, e.g. for range-over-func loops.
+func All[T any](items []T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, item := range items {
+			if !yield(i, item) {
+				return
+			}
+		}
+	}
+}
+
+// Collect consumes the sequence and returns its elements as a slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	result := []T{}
+	for item := range seq {
+		result = append(result, item)
+	}
+
+	return result
+}
+
+// Collect2 consumes the sequence of key-value pairs and returns them as a map. If a key is repeated, the last value is used.
+func Collect2[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	result := map[K]V{}
+	for k, v := range seq {
+		result[k] = v
+	}
+
+	return result
+}
+
+// WeightedSample selects an item with a probability proportional to its weight, using r as the source of randomness.
+// It returns an error if the lengths differ, if a weight is negative or NaN, or if the total weight is not positive.
+func WeightedSample[T any](items []T, weights []float64, r *rand.Rand) (T, error) {
+	var zero T
+	if len(items) != len(weights) {
+		return zero, fmt.Errorf("items and weights have different lengths: %d and %d", len(items), len(weights))
+	}
+
+	total := 0.0
+	last := -1
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) {
+			return zero, fmt.Errorf("invalid weight %v at index %d: weights must be non-negative", w, i)
+		}
+		if w > 0 {
+			total += w
+			last = i
+		}
+	}
+	if total <= 0 || math.IsInf(total, 0) {
+		return zero, errors.New("the total weight must be positive and finite")
+	}
+
+	target := r.Float64() * total
+	cumulative := 0.0
+	for i, w := range weights {
+		cumulative += w
+		if target < cumulative {
+			return items[i], nil
+		}
+	}
+
+	// floating-point rounding can leave the target just above the final cumulative weight
+	return items[last], nil
+}
+
+// RunLength is a value repeated Count times in a row.
+type RunLength[T any] struct {
+	Value T
+	Count int
+}
+
+// RunLengthEncode collapses each run of consecutive equal values into a single RunLength.
+func RunLengthEncode[T comparable](items []T) []RunLength[T] {
+	result := []RunLength[T]{}
+	for _, item := range items {
+		if len(result) > 0 && result[len(result)-1].Value == item {
+			result[len(result)-1].Count++
+			continue
+		}
+		result = append(result, RunLength[T]{Value: item, Count: 1})
+	}
+
+	return result
+}
+
+// RunLengthDecode reverses RunLengthEncode by repeating each value Count times. Runs with a non-positive count are skipped.
+func RunLengthDecode[T any](runs []RunLength[T]) []T {
+	count := 0
+	for _, run := range runs {
+		count += maths.Max(run.Count, 0)
+	}
+
+	result := make([]T, 0, count)
+	for _, run := range runs {
+		for i := 0; i < run.Count; i++ {
+			result = append(result, run.Value)
+		}
+	}
+
+	return result
+}
+
+// ToChannel returns a closed channel holding all the elements of the slice. The buffer fits the whole slice,
+// so no go-routine is involved and nothing leaks if the channel is not drained.
+func ToChannel[T any](items []T) <-chan T {
+	ch := make(chan T, len(items))
+	for _, item := range items {
+		ch <- item
+	}
+	close(ch)
+
+	return ch
+}
+
+// FromChannel receives from the channel until it is closed and returns the elements received.
+func FromChannel[T any](ch <-chan T) []T {
+	result := make([]T, 0, len(ch))
+	for item := range ch {
+		result = append(result, item)
+	}
+
+	return result
+}
@@ -3,15 +3,19 @@ package main
 // Use 'go run <filepath>' to execute this file
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"goutils/slices"
+
 	jira "github.com/andygrunwald/go-jira"
 	"github.com/slack-go/slack"
 	"github.com/xanzy/go-gitlab"
@@ -23,12 +27,22 @@ const (
 	gitLabSupplyProjectID      = 2520
 	maxMergeRequestsToRetrieve = 100
 	jiraBaseURL                = "https://jira.YOURDOMAIN.com"
+	jiraReopenedStatus         = "Reopened"
+	maxConcurrentJiraRequests  = 5
+	gitLabReportIssueTitle     = "Stale merge requests report"
+	// Appended to the comment left on every MR closed by this tool, so that they can be found by the reopen-recent mode
+	closeCommentMarker = "<!-- stale-mr-detector: auto-closed -->"
 )
 
 // Assumes that MR title has the following format "Title [ISSUE-1234]"
 // The first segment is the commit title and the last segment is The JIRA issue enclosed in bracket quotes.
 var mrTitleRegex *regexp.Regexp = regexp.MustCompile(`(.*)\s*\[([A-Z0-9]+-[0-9]+)\]`)
 
+var reopenRecentDays = flag.Int("reopen-recent", 0, "instead of the regular run, reopen MRs closed by this tool within the last N days whose JIRA issue has been reopened")
+var exemptLabels = flag.String("exempt-labels", "keep-open", "comma-separated MR labels that exempt an MR from being reported as stale or closed")
+var output = flag.String("output", "slack", "where to report stale and expired MRs: \"slack\" (channel message) or \"gitlab-issue\" (tracking issue updated on each run)")
+var dmAuthors = flag.Bool("dm-authors", false, "send each author a Slack direct message listing their own MRs, falling back to the channel message for authors who can't be reached")
+
 func main() {
 	gitLabToken, slackToken, jiraToken, isDryRun, err := parseArgs()
 	if err != nil {
@@ -48,13 +62,35 @@ func main() {
 		outputErrorAndExit(err)
 	}
 
-	slackClient := slack.New(slackToken)
+	if *reopenRecentDays > 0 {
+		if _, err := reopenMistakenlyClosedMergeRequests(gitLabClient, jiraClient, *reopenRecentDays, isDryRun); err != nil {
+			outputErrorAndExit(err)
+		}
+		return
+	}
+
+	var r reporter
+	switch *output {
+	case "slack":
+		r = &slackReporter{client: slack.New(slackToken), isDryRun: isDryRun}
+	case "gitlab-issue":
+		r = &gitLabIssueReporter{client: gitLabClient, isDryRun: isDryRun}
+	default:
+		outputErrorAndExit(fmt.Errorf("unknown output %q. Supported outputs: slack, gitlab-issue", *output))
+	}
+
+	// Fail fast before any MR gets closed, rather than after all the GitLab and JIRA work is done
+	if err := r.validate(); err != nil {
+		outputErrorAndExit(err)
+	}
 
 	mrs, err := getMergeRequests(gitLabClient)
 	if err != nil {
 		outputErrorAndExit(err)
 	}
 
+	mrs = excludeExemptMergeRequests(mrs, strings.Split(*exemptLabels, ","))
+
 	staleMRs := getStaleMergeRequests(mrs)
 
 	expiredMRs, err := closeExpiredMergeRequests(mrs, gitLabClient, jiraClient, isDryRun)
@@ -62,36 +98,171 @@ func main() {
 		outputErrorAndExit(err)
 	}
 
+	if err := r.report(sortByStaleness(staleMRs), sortByStaleness(expiredMRs)); err != nil {
+		outputErrorAndExit(err)
+	}
+}
+
+// Publishes the lists of stale and expired merge requests
+type reporter interface {
+	// Checks that the output is usable. Called before any MR gets closed.
+	validate() error
+	report(staleMRs, expiredMRs []*gitlab.MergeRequest) error
+}
+
+// Posts the report to the Slack channel, tagging the authors, or sends it to each author directly with -dm-authors
+type slackReporter struct {
+	client   *slack.Client
+	isDryRun bool
+}
+
+func (r *slackReporter) validate() error {
+	return validateSlackChannel(r.client)
+}
+
+func (r *slackReporter) report(staleMRs, expiredMRs []*gitlab.MergeRequest) error {
 	if len(staleMRs) == 0 && len(expiredMRs) == 0 {
 		fmt.Println("no stale or expired merge requests found. Exiting.")
-		return
+		return nil
 	}
 
-	slackUserIDByGitLabUserID := getSlackUserIDsFromGitLabUserIDs(slackClient, staleMRs, expiredMRs)
+	slackUserIDByGitLabUserID := getSlackUserIDsFromGitLabUserIDs(r.client, staleMRs, expiredMRs)
+
+	if *dmAuthors {
+		staleMRs, expiredMRs = sendDirectMessagesToAuthors(r.client, staleMRs, expiredMRs, slackUserIDByGitLabUserID, r.isDryRun)
+		if len(staleMRs) == 0 && len(expiredMRs) == 0 {
+			fmt.Println("all authors notified by direct message. Exiting.")
+			return nil
+		}
+	}
 
-	inviteUsersToSlackChannel(slackClient, slackUserIDByGitLabUserID, isDryRun)
+	inviteUsersToSlackChannel(r.client, slackUserIDByGitLabUserID, r.isDryRun)
 
-	if err := buildAndPostSlackMessage(slackClient, staleMRs, expiredMRs, slackUserIDByGitLabUserID, isDryRun); err != nil {
-		outputErrorAndExit(err)
+	return buildAndPostSlackMessage(r.client, staleMRs, expiredMRs, slackUserIDByGitLabUserID, r.isDryRun)
+}
+
+// Keeps the report in the description of a GitLab tracking issue, which is created on the first run and updated afterwards
+type gitLabIssueReporter struct {
+	client   *gitlab.Client
+	isDryRun bool
+}
+
+func (r *gitLabIssueReporter) validate() error {
+	// The GitLab client is already used to retrieve the MRs, any problem with it surfaces there
+	return nil
+}
+
+func (r *gitLabIssueReporter) report(staleMRs, expiredMRs []*gitlab.MergeRequest) error {
+	description := buildGitLabIssueDescription(staleMRs, expiredMRs)
+
+	issue, err := findGitLabReportIssue(r.client)
+	if err != nil {
+		return err
+	}
+
+	if r.isDryRun {
+		fmt.Printf("tracking issue description:\n%s", description)
+		return nil
+	}
+
+	if issue == nil {
+		createIssueOptions := &gitlab.CreateIssueOptions{
+			Title:       gitlab.String(gitLabReportIssueTitle),
+			Description: gitlab.String(description),
+		}
+		createdIssue, resp, err := r.client.Issues.CreateIssue(gitLabSupplyProjectID, createIssueOptions)
+		if err != nil {
+			return fmt.Errorf("gitlab - http client error: %w", err)
+		}
+		if resp.StatusCode > 400 {
+			return fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+		}
+		fmt.Printf("tracking issue created: %s\n", createdIssue.WebURL)
+		return nil
+	}
+
+	updateIssueOptions := &gitlab.UpdateIssueOptions{
+		Description: gitlab.String(description),
+	}
+	_, resp, err := r.client.Issues.UpdateIssue(gitLabSupplyProjectID, issue.IID, updateIssueOptions)
+	if err != nil {
+		return fmt.Errorf("gitlab - http client error: %w", err)
+	}
+	if resp.StatusCode > 400 {
+		return fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
 	}
+	fmt.Printf("tracking issue updated: %s\n", issue.WebURL)
+
+	return nil
+}
+
+// Returns the open tracking issue, or nil if there is none yet
+func findGitLabReportIssue(client *gitlab.Client) (*gitlab.Issue, error) {
+	options := &gitlab.ListProjectIssuesOptions{
+		State:  gitlab.String("opened"),
+		Search: gitlab.String(gitLabReportIssueTitle),
+		In:     gitlab.String("title"),
+	}
+
+	issues, resp, err := client.Issues.ListProjectIssues(gitLabSupplyProjectID, options)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab - http client error: %w", err)
+	}
+	if resp.StatusCode > 400 {
+		return nil, fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+	}
+
+	// The search also matches titles that only contain the report title
+	issue, _ := slices.FindLast(issues, func(issue *gitlab.Issue) bool {
+		return issue.Title == gitLabReportIssueTitle
+	})
+
+	return issue, nil
+}
+
+func buildGitLabIssueDescription(staleMRs, expiredMRs []*gitlab.MergeRequest) string {
+	formatLine := func(mr *gitlab.MergeRequest) string {
+		title, _ := parseMRTitle(mr.Title)
+		line := fmt.Sprintf("- !%d %s", mr.IID, strings.TrimSpace(title))
+		if jiraIssueID := getJiraIssueID(mr.Title); jiraIssueID != "" {
+			line += fmt.Sprintf(" [%s](%s/browse/%s)", jiraIssueID, jiraBaseURL, jiraIssueID)
+		}
+		return line + fmt.Sprintf(" - @%s (%s)", mr.Author.Username, formatAge(mr))
+	}
+
+	description := fmt.Sprintf("_Last updated by stale-mr-detector on %s._\n\n", time.Now().Format("2006-01-02"))
+	if len(staleMRs) == 0 && len(expiredMRs) == 0 {
+		return description + "No stale or expired merge requests found.\n"
+	}
+	if len(staleMRs) > 0 {
+		description += "### Stale MRs (more than 2 months old without any updates)\n\nThese MRs will be automatically closed in 1 month if they aren't updated.\n\n"
+		description += slices.JoinToString(staleMRs, "\n", formatLine) + "\n\n"
+	}
+	if len(expiredMRs) > 0 {
+		description += "### MRs that have been closed (due to staleness or the associated JIRA issue being closed)\n\n"
+		description += slices.JoinToString(expiredMRs, "\n", formatLine) + "\n"
+	}
+
+	return description
 }
 
 func parseArgs() (string, string, string, bool, error) {
-	args := os.Args
-	if len(args) < 4 {
-		return "", "", "", false, fmt.Errorf("missing args. Usage: [gitlabtoken] [slacktoken] [jiratoken] [dryrun]")
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 3 {
+		return "", "", "", false, fmt.Errorf("missing args. Usage: [flags] [gitlabtoken] [slacktoken] [jiratoken] [dryrun]")
 	}
 
-	gitLabToken := args[1]
-	slackToken := args[2]
-	jiraToken := args[3]
+	gitLabToken := args[0]
+	slackToken := args[1]
+	jiraToken := args[2]
 
 	isDryRun := false
-	if len(args) == 5 {
-		isDryRun, _ = strconv.ParseBool(args[4])
+	if len(args) == 4 {
+		isDryRun, _ = strconv.ParseBool(args[3])
 	}
 	if isDryRun {
-		fmt.Println("dryrun option enabled, GitLab MRs will not be updated and Slack messages will not be posted.")
+		fmt.Println("dryrun option enabled, GitLab MRs and issues will not be updated and Slack messages will not be posted.")
 	}
 
 	return gitLabToken, slackToken, jiraToken, isDryRun, nil
@@ -118,6 +289,18 @@ func getMergeRequests(client *gitlab.Client) ([]*gitlab.MergeRequest, error) {
 	return mergeRequests, nil
 }
 
+// Exclude merge requests bearing any of the exemption labels, so that they are neither reported as stale nor closed
+func excludeExemptMergeRequests(mrs []*gitlab.MergeRequest, labels []string) []*gitlab.MergeRequest {
+	labels = slices.NonZeroValues(slices.Map(labels, strings.TrimSpace))
+	exemptMRs, nonExemptMRs := slices.Partition(mrs, func(mr *gitlab.MergeRequest) bool {
+		return slices.Any(mr.Labels, func(label string) bool { return slices.Contains(labels, label) })
+	})
+
+	fmt.Printf("internal IDs of exempt MRs: %s\n", extractMergeRequestIIDs(exemptMRs))
+
+	return nonExemptMRs
+}
+
 // Get merge requests that haven't been updated for over 2 months
 func getStaleMergeRequests(mrs []*gitlab.MergeRequest) []*gitlab.MergeRequest {
 	staleMRs := []*gitlab.MergeRequest{}
@@ -137,7 +320,18 @@ func getStaleMergeRequests(mrs []*gitlab.MergeRequest) []*gitlab.MergeRequest {
 func closeExpiredMergeRequests(mrs []*gitlab.MergeRequest, gitlabClient *gitlab.Client, jiraClient *jira.Client, isDryRun bool) ([]*gitlab.MergeRequest, error) {
 	expiredMRs := []*gitlab.MergeRequest{}
 	threeMonthsAgo := time.Now().AddDate(0, -3, 0)
-	for _, mr := range mrs {
+
+	// Fetch the JIRA statuses concurrently, but evaluate them in order below so that the first error is returned as before
+	jiraIssueStatuses := slices.MapConcurrent(mrs, maxConcurrentJiraRequests, func(mr *gitlab.MergeRequest) jiraIssueStatus {
+		jiraIssueID := getJiraIssueID(mr.Title)
+		if mr.UpdatedAt.Before(threeMonthsAgo) || jiraIssueID == "" {
+			return jiraIssueStatus{}
+		}
+		name, found, err := getJiraIssueStatus(jiraClient, jiraIssueID)
+		return jiraIssueStatus{name: name, found: found, err: err}
+	})
+
+	for i, mr := range mrs {
 		if mr.UpdatedAt.Before(threeMonthsAgo) {
 			expiredMRs = append(expiredMRs, mr)
 			continue
@@ -149,18 +343,15 @@ func closeExpiredMergeRequests(mrs []*gitlab.MergeRequest, gitlabClient *gitlab.
 			continue
 		}
 
-		issue, resp, err := jiraClient.Issue.Get(jiraIssueID, nil)
-		if err != nil {
-			if resp != nil && resp.StatusCode == 404 {
-				fmt.Printf("JIRA issue ID attached to MR does not exist: %d. Skipping status check.\n", mr.IID)
-				continue
-			}
-			if resp != nil && resp.StatusCode > 400 {
-				return nil, fmt.Errorf("jira - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
-			}
-			return nil, fmt.Errorf("jira - http client error: %w", err)
+		status := jiraIssueStatuses[i]
+		if status.err != nil {
+			return nil, status.err
+		}
+		if !status.found {
+			fmt.Printf("JIRA issue ID attached to MR does not exist: %d. Skipping status check.\n", mr.IID)
+			continue
 		}
-		if issue.Fields.Status.Name == "Closed" {
+		if status.name == "Closed" {
 			expiredMRs = append(expiredMRs, mr)
 			continue
 		}
@@ -173,21 +364,140 @@ func closeExpiredMergeRequests(mrs []*gitlab.MergeRequest, gitlabClient *gitlab.
 	}
 
 	for _, mr := range expiredMRs {
-		updateMergeRequestOptions := &gitlab.UpdateMergeRequestOptions{
-			StateEvent: gitlab.String("close"),
+		createNoteOptions := &gitlab.CreateMergeRequestNoteOptions{
+			Body: gitlab.String("This MR has been closed automatically due to staleness or the associated JIRA issue being closed.\n" + closeCommentMarker),
 		}
-		_, resp, err := gitlabClient.MergeRequests.UpdateMergeRequest(gitLabSupplyProjectID, mr.IID, updateMergeRequestOptions)
+		_, resp, err := gitlabClient.Notes.CreateMergeRequestNote(gitLabSupplyProjectID, mr.IID, createNoteOptions)
 		if err != nil {
 			return nil, fmt.Errorf("gitlab - http client error: %w", err)
 		}
 		if resp.StatusCode > 400 {
 			return nil, fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
 		}
+
+		if err := updateMergeRequestState(gitlabClient, mr, "close"); err != nil {
+			return nil, err
+		}
 	}
 
 	return expiredMRs, nil
 }
 
+// Reopen merge requests closed by this tool within the last N days whose associated JIRA issue has been reopened
+func reopenMistakenlyClosedMergeRequests(gitlabClient *gitlab.Client, jiraClient *jira.Client, days int, isDryRun bool) ([]*gitlab.MergeRequest, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	options := &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.String("closed"),
+		UpdatedAfter: &since,
+		ListOptions:  gitlab.ListOptions{PerPage: maxMergeRequestsToRetrieve},
+	}
+
+	closedMRs, resp, err := gitlabClient.MergeRequests.ListProjectMergeRequests(gitLabSupplyProjectID, options)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab - http client error: %w", err)
+	}
+	if resp.StatusCode > 400 {
+		return nil, fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+	}
+
+	reopenedMRs := []*gitlab.MergeRequest{}
+	for _, mr := range closedMRs {
+		jiraIssueID := getJiraIssueID(mr.Title)
+		if jiraIssueID == "" {
+			continue
+		}
+
+		isClosedByThisTool, err := wasClosedByThisToolSince(gitlabClient, mr, since)
+		if err != nil {
+			return nil, err
+		}
+		if !isClosedByThisTool {
+			continue
+		}
+
+		status, found, err := getJiraIssueStatus(jiraClient, jiraIssueID)
+		if err != nil {
+			return nil, err
+		}
+		if found && status == jiraReopenedStatus {
+			reopenedMRs = append(reopenedMRs, mr)
+		}
+	}
+
+	fmt.Printf("internal IDs of MRs to reopen: %s\n", extractMergeRequestIIDs(reopenedMRs))
+
+	if isDryRun {
+		return reopenedMRs, nil
+	}
+
+	for _, mr := range reopenedMRs {
+		if err := updateMergeRequestState(gitlabClient, mr, "reopen"); err != nil {
+			return nil, err
+		}
+	}
+
+	return reopenedMRs, nil
+}
+
+// Checks if the merge request has a close comment left by this tool after the given time
+func wasClosedByThisToolSince(client *gitlab.Client, mr *gitlab.MergeRequest, since time.Time) (bool, error) {
+	options := &gitlab.ListMergeRequestNotesOptions{
+		ListOptions: gitlab.ListOptions{PerPage: maxMergeRequestsToRetrieve},
+		OrderBy:     gitlab.String("created_at"),
+		Sort:        gitlab.String("desc"),
+	}
+
+	notes, resp, err := client.Notes.ListMergeRequestNotes(gitLabSupplyProjectID, mr.IID, options)
+	if err != nil {
+		return false, fmt.Errorf("gitlab - http client error: %w", err)
+	}
+	if resp.StatusCode > 400 {
+		return false, fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+	}
+
+	return slices.Any(notes, func(note *gitlab.Note) bool {
+		return strings.Contains(note.Body, closeCommentMarker) && note.CreatedAt != nil && note.CreatedAt.After(since)
+	}), nil
+}
+
+// Applies a state event ("close" or "reopen") to the merge request
+func updateMergeRequestState(client *gitlab.Client, mr *gitlab.MergeRequest, stateEvent string) error {
+	updateMergeRequestOptions := &gitlab.UpdateMergeRequestOptions{
+		StateEvent: gitlab.String(stateEvent),
+	}
+	_, resp, err := client.MergeRequests.UpdateMergeRequest(gitLabSupplyProjectID, mr.IID, updateMergeRequestOptions)
+	if err != nil {
+		return fmt.Errorf("gitlab - http client error: %w", err)
+	}
+	if resp.StatusCode > 400 {
+		return fmt.Errorf("gitlab - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+	}
+
+	return nil
+}
+
+type jiraIssueStatus struct {
+	name  string
+	found bool
+	err   error
+}
+
+// Returns the status name of the JIRA issue, or false if the issue does not exist
+func getJiraIssueStatus(client *jira.Client, jiraIssueID string) (string, bool, error) {
+	issue, resp, err := client.Issue.Get(jiraIssueID, nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == 404 {
+			return "", false, nil
+		}
+		if resp != nil && resp.StatusCode > 400 {
+			return "", false, fmt.Errorf("jira - invalid request. Status code: %s. Body: %s", resp.Status, extractResponseBody(resp.Response))
+		}
+		return "", false, fmt.Errorf("jira - http client error: %w", err)
+	}
+
+	return issue.Fields.Status.Name, true, nil
+}
+
 func extractResponseBody(resp *http.Response) string {
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
@@ -198,13 +508,9 @@ func extractResponseBody(resp *http.Response) string {
 }
 
 func extractMergeRequestIIDs(mrs []*gitlab.MergeRequest) string {
-	iids := []string{}
-	for _, mr := range mrs {
-		iidString := strconv.FormatInt(int64(mr.IID), 10)
-		iids = append(iids, iidString)
-	}
-
-	return strings.Join(iids, ",")
+	return slices.JoinToString(mrs, ",", func(mr *gitlab.MergeRequest) string {
+		return strconv.FormatInt(int64(mr.IID), 10)
+	})
 }
 
 func getSlackUserIDsFromGitLabUserIDs(client *slack.Client, staleMRs, expiredMRs []*gitlab.MergeRequest) map[string]string {
@@ -235,6 +541,15 @@ func getSlackUserIDsFromGitLabUserIDs(client *slack.Client, staleMRs, expiredMRs
 	return slackUserIDByGitLabUserID
 }
 
+func validateSlackChannel(client *slack.Client) error {
+	_, err := client.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: slackChannelID})
+	if err != nil {
+		return fmt.Errorf("slack - error looking up channel <%s>, check that the channel ID is valid and the bot has access to it: %w", slackChannelID, err)
+	}
+
+	return nil
+}
+
 func inviteUsersToSlackChannel(client *slack.Client, slackUserIDByGitLabUserID map[string]string, isDryRun bool) {
 	slackUserIDs := []string{}
 	for _, slackUserID := range slackUserIDByGitLabUserID {
@@ -254,7 +569,74 @@ func inviteUsersToSlackChannel(client *slack.Client, slackUserIDByGitLabUserID m
 	}
 }
 
+// Send each author a direct message listing only their own MRs.
+// Returns the MRs whose authors couldn't be reached, so that they can be posted to the channel instead.
+func sendDirectMessagesToAuthors(client *slack.Client, staleMRs, expiredMRs []*gitlab.MergeRequest, slackUserIDByGitLabUserID map[string]string, isDryRun bool) ([]*gitlab.MergeRequest, []*gitlab.MergeRequest) {
+	staleMRsByAuthor := slices.GroupBy(staleMRs, getAuthorUsername)
+	expiredMRsByAuthor := slices.GroupBy(expiredMRs, getAuthorUsername)
+	authors := slices.Unique(append(slices.Map(staleMRs, getAuthorUsername), slices.Map(expiredMRs, getAuthorUsername)...))
+	sort.Strings(authors)
+
+	undeliveredStaleMRs := []*gitlab.MergeRequest{}
+	undeliveredExpiredMRs := []*gitlab.MergeRequest{}
+	for _, author := range authors {
+		authorStaleMRs := staleMRsByAuthor[author]
+		authorExpiredMRs := expiredMRsByAuthor[author]
+
+		slackUserID, ok := slackUserIDByGitLabUserID[author]
+		if !ok {
+			fmt.Printf("slack - no user id found for author %s, falling back to the channel message\n", author)
+			undeliveredStaleMRs = append(undeliveredStaleMRs, authorStaleMRs...)
+			undeliveredExpiredMRs = append(undeliveredExpiredMRs, authorExpiredMRs...)
+			continue
+		}
+
+		if isDryRun {
+			fmt.Printf("direct message to %s - internal IDs of stale MRs: %s, internal IDs of expired MRs: %s\n", author, extractMergeRequestIIDs(authorStaleMRs), extractMergeRequestIIDs(authorExpiredMRs))
+			continue
+		}
+
+		if err := postDirectMessage(client, slackUserID, buildSlackMessageBlocks(authorStaleMRs, authorExpiredMRs, slackUserIDByGitLabUserID)); err != nil {
+			fmt.Println(err)
+			fmt.Printf("falling back to the channel message for author %s\n", author)
+			undeliveredStaleMRs = append(undeliveredStaleMRs, authorStaleMRs...)
+			undeliveredExpiredMRs = append(undeliveredExpiredMRs, authorExpiredMRs...)
+		}
+	}
+
+	return undeliveredStaleMRs, undeliveredExpiredMRs
+}
+
+func postDirectMessage(client *slack.Client, slackUserID string, blocks []slack.Block) error {
+	channel, _, _, err := client.OpenConversation(&slack.OpenConversationParameters{Users: []string{slackUserID}})
+	if err != nil {
+		return fmt.Errorf("slack - error opening direct message conversation with user <%s>: %w", slackUserID, err)
+	}
+
+	_, _, err = client.PostMessage(channel.ID, slack.MsgOptionBlocks(blocks...))
+	if err != nil {
+		return fmt.Errorf("slack - error posting direct message to user <%s>: %w", slackUserID, err)
+	}
+
+	return nil
+}
+
 func buildAndPostSlackMessage(client *slack.Client, staleMRs, expiredMRs []*gitlab.MergeRequest, slackUserIDByGitLabUserID map[string]string, isDryRun bool) error {
+	blocks := buildSlackMessageBlocks(staleMRs, expiredMRs, slackUserIDByGitLabUserID)
+
+	if isDryRun {
+		return nil
+	}
+
+	_, _, err := client.PostMessage(slackChannelID, slack.MsgOptionBlocks(blocks...))
+	if err != nil {
+		return fmt.Errorf("slack - error posting message to channel <%v>: %w", slackChannelID, err)
+	}
+
+	return nil
+}
+
+func buildSlackMessageBlocks(staleMRs, expiredMRs []*gitlab.MergeRequest, slackUserIDByGitLabUserID map[string]string) []slack.Block {
 	blocks := []slack.Block{}
 	if len(staleMRs) > 0 {
 		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*Stale MRs (more than 2 months old without any updates):*\nThese MRs will be automatically closed in 1 month if they aren't updated", false, false), nil, nil))
@@ -265,7 +647,7 @@ func buildAndPostSlackMessage(client *slack.Client, staleMRs, expiredMRs []*gitl
 			if !ok {
 				slackUserID = "Unknown"
 			}
-			text += fmt.Sprintf(":alarm_clock: <%s|!%d %s> %s - <@%s>\n", mr.WebURL, mr.IID, title, jiraURL, slackUserID)
+			text += fmt.Sprintf(":alarm_clock: <%s|!%d %s> %s - <@%s> (%s)\n", mr.WebURL, mr.IID, title, jiraURL, slackUserID, formatAge(mr))
 		}
 		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
 	}
@@ -278,21 +660,32 @@ func buildAndPostSlackMessage(client *slack.Client, staleMRs, expiredMRs []*gitl
 			if !ok {
 				slackUserID = "Unknown"
 			}
-			text += fmt.Sprintf(":x: <%s|!%d %s> %s - <@%s>\n", mr.WebURL, mr.IID, title, jiraURL, slackUserID)
+			text += fmt.Sprintf(":x: <%s|!%d %s> %s - <@%s> (%s)\n", mr.WebURL, mr.IID, title, jiraURL, slackUserID, formatAge(mr))
 		}
 		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil))
 	}
 
-	if isDryRun {
-		return nil
-	}
+	return blocks
+}
 
-	_, _, err := client.PostMessage(slackChannelID, slack.MsgOptionBlocks(blocks...))
-	if err != nil {
-		return fmt.Errorf("slack - error posting message to channel <%v>: %w", slackChannelID, err)
+// Sort merge requests oldest-first by their last update, so the worst offenders come first
+func sortByStaleness(mrs []*gitlab.MergeRequest) []*gitlab.MergeRequest {
+	return slices.SortBy(mrs, func(mr *gitlab.MergeRequest) int64 {
+		return mr.UpdatedAt.UnixNano()
+	})
+}
+
+// Formats the time since the last update of a merge request, e.g. "78 days"
+func formatAge(mr *gitlab.MergeRequest) string {
+	days := int(time.Since(*mr.UpdatedAt).Hours() / 24)
+	if days == 1 {
+		return "1 day"
 	}
+	return fmt.Sprintf("%d days", days)
+}
 
-	return nil
+func getAuthorUsername(mr *gitlab.MergeRequest) string {
+	return mr.Author.Username
 }
 
 // Extracts the title and the JIRA issue
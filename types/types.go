@@ -2,6 +2,9 @@ package types
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"golang.org/x/exp/constraints"
 )
@@ -10,6 +13,43 @@ func ToPointer[T any](t T) *T {
 	return &t
 }
 
+// NilIfZero returns a pointer to the value, or nil if it is the zero value.
+func NilIfZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// ValueOrZero returns the value pointed to, or the zero value if the pointer is nil.
+func ValueOrZero[T any](p *T) T {
+	if p == nil {
+		return *new(T)
+	}
+	return *p
+}
+
+// DerefAll dereferences each pointer, using the zero value for nil pointers.
+func DerefAll[T any](ptrs []*T) []T {
+	result := make([]T, 0, len(ptrs))
+	for _, p := range ptrs {
+		result = append(result, ValueOrZero(p))
+	}
+	return result
+}
+
+// DerefNonNil dereferences each pointer, skipping nil pointers.
+func DerefNonNil[T any](ptrs []*T) []T {
+	result := make([]T, 0, len(ptrs))
+	for _, p := range ptrs {
+		if p != nil {
+			result = append(result, *p)
+		}
+	}
+	return result
+}
+
 func ToStringFromBool(b bool) string {
 	if b {
 		return "true"
@@ -17,6 +57,26 @@ func ToStringFromBool(b bool) string {
 	return "false"
 }
 
+// FormatBool returns trueStr or falseStr depending on the value, e.g. to render "Yes"/"No" or "on"/"off".
+func FormatBool(b bool, trueStr, falseStr string) string {
+	if b {
+		return trueStr
+	}
+	return falseStr
+}
+
+// ParseBoolWith reverses FormatBool, returning an error if s is neither trueStr nor falseStr.
+func ParseBoolWith(s, trueStr, falseStr string) (bool, error) {
+	switch s {
+	case trueStr:
+		return true, nil
+	case falseStr:
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value %q: must be %q or %q", s, trueStr, falseStr)
+	}
+}
+
 func ToIntFromBool[T constraints.Integer](b bool) T {
 	if b {
 		return 1
@@ -35,3 +95,248 @@ func ErrorAs[T error](err error) (T, bool) {
 	}
 	return *new(T), false
 }
+
+// Assert returns v as a T, or an error naming the expected and actual types if v doesn't hold a T (including when v is nil).
+func Assert[T any](v any) (T, error) {
+	t, ok := v.(T)
+	if !ok {
+		return t, fmt.Errorf("type assertion failed: expected %v, got %T", reflect.TypeFor[T](), v)
+	}
+	return t, nil
+}
+
+// MustAssert is like Assert, but panics if v doesn't hold a T.
+func MustAssert[T any](v any) T {
+	t, err := Assert[T](v)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Must0 panics with the error if it is not nil. Like the other Must functions, it is meant for initialization code.
+func Must0(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Must returns the value, or panics with the error if it is not nil.
+func Must[T any](t T, err error) T {
+	Must0(err)
+	return t
+}
+
+// Must2 returns both values, or panics with the error if it is not nil.
+func Must2[A, B any](a A, b B, err error) (A, B) {
+	Must0(err)
+	return a, b
+}
+
+// Must3 returns the three values, or panics with the error if it is not nil.
+func Must3[A, B, C any](a A, b B, c C, err error) (A, B, C) {
+	Must0(err)
+	return a, b, c
+}
+
+// EqualBy compares two values with a custom equality function, e.g. for structs that are not comparable.
+func EqualBy[T any](a, b T, eq func(a, b T) bool) bool {
+	return eq(a, b)
+}
+
+// EqualByKey compares two values by the comparable key derived from each of them.
+func EqualByKey[T any, K comparable](a, b T, keyFn func(T) K) bool {
+	return keyFn(a) == keyFn(b)
+}
+
+// DeepClone returns a deep copy of v, recursively copying pointers, slices, maps, arrays, structs and interfaces.
+// Unexported struct fields cannot be set via reflection, so they are copied shallowly; channels and functions are shared too.
+// Cycles through pointers and maps are supported and reproduced in the copy.
+func DeepClone[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type())
+	deepCopy(dst.Elem(), src, map[visitedRef]reflect.Value{})
+	return *dst.Interface().(*T)
+}
+
+// visitedRef identifies a pointer or map that has already been copied.
+type visitedRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func deepCopy(dst, src reflect.Value, visited map[visitedRef]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		ref := visitedRef{ptr: src.Pointer(), typ: src.Type()}
+		if c, ok := visited[ref]; ok {
+			dst.Set(c)
+			return
+		}
+		c := reflect.New(src.Type().Elem())
+		visited[ref] = c
+		deepCopy(c.Elem(), src.Elem(), visited)
+		dst.Set(c)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		ref := visitedRef{ptr: src.Pointer(), typ: src.Type()}
+		if c, ok := visited[ref]; ok {
+			dst.Set(c)
+			return
+		}
+		c := reflect.MakeMapWithSize(src.Type(), src.Len())
+		visited[ref] = c
+		for it := src.MapRange(); it.Next(); {
+			k := reflect.New(src.Type().Key()).Elem()
+			deepCopy(k, it.Key(), visited)
+			v := reflect.New(src.Type().Elem()).Elem()
+			deepCopy(v, it.Value(), visited)
+			c.SetMapIndex(k, v)
+		}
+		dst.Set(c)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		c := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(c.Index(i), src.Index(i), visited)
+		}
+		dst.Set(c)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(dst.Index(i), src.Index(i), visited)
+		}
+	case reflect.Struct:
+		dst.Set(src) // copies unexported fields shallowly
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				deepCopy(dst.Field(i), src.Field(i), visited)
+			}
+		}
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		c := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(c, src.Elem(), visited)
+		dst.Set(c)
+	default:
+		dst.Set(src)
+	}
+}
+
+// StructToMap converts a struct, or a pointer to a struct, into a map keyed by field name.
+// Like encoding/json, it uses the name from the json tag when present, skips unexported fields and fields tagged with "-",
+// omits empty values of fields tagged with omitempty, and promotes the fields of embedded structs without a json tag.
+// Fields of the outer struct take precedence over promoted fields with the same name. Values are not converted recursively.
+func StructToMap(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("cannot convert a nil pointer to a map")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot convert %T to a map, a struct is expected", v)
+	}
+
+	result := map[string]any{}
+	addStructFields(result, rv)
+	return result, nil
+}
+
+func addStructFields(result map[string]any, rv reflect.Value) {
+	embedded := []reflect.Value{}
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" {
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				// promoted fields can come from unexported embedded structs, like in encoding/json
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if strings.Contains(","+options+",", ",omitempty,") && isEmptyValue(fv) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		result[name] = fv.Interface()
+	}
+
+	for _, fv := range embedded {
+		promoted := map[string]any{}
+		addStructFields(promoted, fv)
+		for k, v := range promoted {
+			if _, ok := result[k]; !ok {
+				result[k] = v
+			}
+		}
+	}
+}
+
+// isEmptyValue follows the definition of empty used by the omitempty option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// OneOf verifies if the value is one of the allowed values.
+func OneOf[T comparable](value T, allowed ...T) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOneOf returns an error listing the allowed values if the value is not one of them.
+func ValidateOneOf[T comparable](value T, allowed ...T) error {
+	if OneOf(value, allowed...) {
+		return nil
+	}
+	if len(allowed) == 0 {
+		return fmt.Errorf("invalid value %v: no values are allowed", value)
+	}
+
+	names := make([]string, 0, len(allowed))
+	for _, a := range allowed {
+		names = append(names, fmt.Sprint(a))
+	}
+	return fmt.Errorf("invalid value %v: must be one of %s", value, strings.Join(names, ", "))
+}